@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"os"
 
 	"github.com/Pauli-Group/Hegemon/consensus/bench/sim"
+	"github.com/Pauli-Group/Hegemon/consensus/bench/sim/score"
 )
 
 func main() {
@@ -17,6 +20,22 @@ func main() {
 		seed         = flag.Int64("seed", 42, "random seed for jitter")
 		smoke        = flag.Bool("smoke", false, "run a short smoke benchmark")
 		jsonOut      = flag.Bool("json", false, "emit JSON instead of text")
+
+		degree      = flag.Int("degree", 6, "mesh (push) peers per node")
+		fanOut      = flag.Int("fan-out", 3, "lazy (IHAVE/IWANT pull) peers per node")
+		bandwidth   = flag.Int("link-bandwidth-bytes", 25*1024*1024, "per-link bandwidth in bytes/sec")
+		rttMeanMs   = flag.Float64("rtt-mean-ms", 50, "mean per-link round-trip time in ms")
+		rttStddevMs = flag.Float64("rtt-stddev-ms", 15, "stddev of per-link round-trip time in ms")
+		lossRate    = flag.Float64("loss-rate", 0, "probability in [0,1] that a gossip message is dropped")
+		topology    = flag.String("topology", "random-regular", "peer mesh topology: random-regular or small-world")
+		scoring     = flag.Bool("score", false, "enable GossipSub v1.1-style peer scoring with default params")
+
+		pqScheme        = flag.String("pq-scheme", "dilithium2", "PQ signature scheme for CPU-bound cost: dilithium2, dilithium3, falcon512, or sphincsplus128s")
+		cores           = flag.Int("cores", 1, "cores available for parallel signature verification")
+		verifyBatchSize = flag.Int("verify-batch-size", 1, "signatures verified together in one batch")
+		batchSpeedup    = flag.Float64("batch-speedup", 1, "per-signature speedup from batching, applied once verify-batch-size > 1")
+
+		live = flag.Bool("live", false, "gossip real bytes over libp2p instead of estimating analytically, using in-process hosts")
 	)
 	flag.Parse()
 
@@ -27,13 +46,47 @@ func main() {
 		}
 	}
 
-	metrics := sim.Simulate(sim.Options{
+	var scoreParams *score.Params
+	if *scoring {
+		p := score.DefaultParams()
+		scoreParams = &p
+	}
+
+	opts := sim.Options{
 		Miners:           *miners,
 		PayloadBytes:     *payloadBytes,
 		Iterations:       iterCount,
 		PQSignatureBytes: *pqBytes,
 		Seed:             *seed,
-	})
+
+		Degree:                   *degree,
+		FanOut:                   *fanOut,
+		LinkBandwidthBytesPerSec: *bandwidth,
+		RTTMeanMs:                *rttMeanMs,
+		RTTStddevMs:              *rttStddevMs,
+		LossRate:                 *lossRate,
+		Topology:                 parseTopology(*topology),
+		ScoreParams:              scoreParams,
+
+		PQScheme:        parsePQScheme(*pqScheme),
+		Cores:           *cores,
+		VerifyBatchSize: *verifyBatchSize,
+		BatchSpeedup:    *batchSpeedup,
+	}
+
+	var (
+		metrics sim.Metrics
+		err     error
+	)
+	if *live {
+		metrics, err = sim.RunLive(context.Background(), sim.LiveOptions{Options: opts})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "netbench: live run failed: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		metrics = sim.Simulate(opts)
+	}
 
 	if *jsonOut {
 		blob, err := json.MarshalIndent(metrics, "", "  ")
@@ -45,13 +98,46 @@ func main() {
 	}
 
 	fmt.Printf(
-		"netbench: miners=%d payload=%dB pq_sig=%dB iterations=%d msgs/s=%.2f latency=%.2fms duration=%dms\n",
+		"netbench: miners=%d payload=%dB pq_sig=%dB iterations=%d msgs/s=%.2f latency=%.2fms (p50=%.2f p95=%.2f p99=%.2f) dup=%.2f%% coverage=%dms net_bound=%.2fms cpu_bound=%.2fms grafts=%d prunes=%d graylistings=%d\n",
 		metrics.Miners,
 		metrics.PayloadBytes,
 		metrics.PQSignatureBytes,
 		metrics.Iterations,
 		metrics.MessagesPerSec,
 		metrics.AvgLatencyMs,
-		metrics.DurationMs,
+		metrics.P50LatencyMs,
+		metrics.P95LatencyMs,
+		metrics.P99LatencyMs,
+		metrics.DuplicateRatio*100,
+		metrics.TimeToFullCoverageMs,
+		metrics.NetBoundMs,
+		metrics.CPUBoundMs,
+		metrics.Grafts,
+		metrics.Prunes,
+		metrics.Graylistings,
 	)
 }
+
+func parseTopology(name string) sim.Topology {
+	switch name {
+	case "small-world":
+		return sim.TopologySmallWorld
+	case "explicit":
+		return sim.TopologyExplicit
+	default:
+		return sim.TopologyRandomRegular
+	}
+}
+
+func parsePQScheme(name string) sim.PQScheme {
+	switch name {
+	case "dilithium3":
+		return sim.Dilithium3
+	case "falcon512":
+		return sim.Falcon512
+	case "sphincsplus128s":
+		return sim.SPHINCSPlus128s
+	default:
+		return sim.Dilithium2
+	}
+}
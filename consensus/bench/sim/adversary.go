@@ -0,0 +1,76 @@
+package sim
+
+// AdversaryKind classifies the misbehavior a simulated Byzantine miner exhibits.
+type AdversaryKind int
+
+const (
+	// AdversaryEquivocator gossips two conflicting payloads for the same
+	// message, one to each half of its mesh peers.
+	AdversaryEquivocator AdversaryKind = iota + 1
+	// AdversaryEclipse drops every message it would otherwise relay to its
+	// EclipseTargets, attempting to isolate them from the rest of the network.
+	AdversaryEclipse
+	// AdversaryLazy never forwards anything it receives: no mesh push, no
+	// lazy IHAVE announce.
+	AdversaryLazy
+	// AdversarySpammer inflates the messages it originates or relays by
+	// SpamMultiplier to waste peer bandwidth.
+	AdversarySpammer
+)
+
+// Adversary describes one misbehaving miner for adversarial/Byzantine modeling.
+type Adversary struct {
+	PeerIndex int
+	Kind      AdversaryKind
+	// EclipseTargets lists the peer indices an AdversaryEclipse drops traffic for.
+	EclipseTargets []int
+	// SpamMultiplier scales perMessageBytes for an AdversarySpammer; defaults to 8 if <= 1.
+	SpamMultiplier float64
+}
+
+// adversarySet indexes Options.Adversaries by peer for cheap lookups during simulation.
+type adversarySet map[int]Adversary
+
+func buildAdversarySet(adversaries []Adversary) adversarySet {
+	set := make(adversarySet, len(adversaries))
+	for _, a := range adversaries {
+		if a.Kind == AdversarySpammer && a.SpamMultiplier <= 1 {
+			a.SpamMultiplier = 8
+		}
+		set[a.PeerIndex] = a
+	}
+	return set
+}
+
+func (s adversarySet) kindOf(peer int) (Adversary, bool) {
+	a, ok := s[peer]
+	return a, ok
+}
+
+func (s adversarySet) isLazy(peer int) bool {
+	a, ok := s[peer]
+	return ok && a.Kind == AdversaryLazy
+}
+
+// eclipses reports whether from is an eclipse attacker dropping traffic bound for to.
+func (s adversarySet) eclipses(from, to int) bool {
+	a, ok := s[from]
+	if !ok || a.Kind != AdversaryEclipse {
+		return false
+	}
+	for _, target := range a.EclipseTargets {
+		if target == to {
+			return true
+		}
+	}
+	return false
+}
+
+// messageSize returns perMessageBytes, inflated if from is a spammer.
+func (s adversarySet) messageSize(from, perMessageBytes int) int {
+	a, ok := s[from]
+	if !ok || a.Kind != AdversarySpammer {
+		return perMessageBytes
+	}
+	return int(float64(perMessageBytes) * a.SpamMultiplier)
+}
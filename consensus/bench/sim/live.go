@@ -0,0 +1,226 @@
+package sim
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/host"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+)
+
+// liveTopicName is the gossipsub topic netbench publishes on in live mode.
+const liveTopicName = "hegemon/netbench/v1"
+
+// settleDelay is how long RunLive waits for the mesh to form before publishing,
+// and again after the last publish before tearing hosts down, so slow joiners
+// and in-flight IWANT pulls aren't counted as dropped.
+const settleDelay = 250 * time.Millisecond
+
+// LiveOptions configures RunLive. The Options fields it embeds (Miners,
+// PayloadBytes, PQSignatureBytes, Iterations) are interpreted the same way
+// Simulate interprets them; the other Options fields, Seed included, are not
+// used in live mode.
+type LiveOptions struct {
+	Options
+	// Listen, if set, is meant to run a single real libp2p host bound to this
+	// multiaddr instead of spinning up Options.Miners hosts in-process, so a
+	// run can be spread across processes/machines with Connect. NOT YET
+	// IMPLEMENTED: setting it always returns an error from RunLive. It is not
+	// exposed as a netbench CLI flag until runLiveDistributed is real.
+	Listen string
+	// Connect lists peer multiaddrs (each including a trailing /p2p/<id>) this
+	// host would dial after it starts listening. Only meaningful with Listen
+	// set; see the Listen doc for its current unimplemented status.
+	Connect []string
+}
+
+// RunLive gossips real PayloadBytes+PQSignatureBytes blobs across libp2p hosts
+// running gossipsub, measuring wall-clock delivery latency and goodput. It
+// populates the same Metrics struct Simulate does, so analytic and empirical
+// runs are directly comparable.
+func RunLive(ctx context.Context, opts LiveOptions) (Metrics, error) {
+	if opts.Listen != "" {
+		return runLiveDistributed(ctx, opts)
+	}
+	return runLiveInProcess(ctx, opts.Options)
+}
+
+// runLiveInProcess spins up cfg.Miners libp2p hosts on a mocknet (in-memory
+// transport, real gossipsub routing) within this process.
+func runLiveInProcess(ctx context.Context, opts Options) (Metrics, error) {
+	cfg := normalize(opts)
+
+	net := mocknet.New()
+	hosts := make([]host.Host, cfg.Miners)
+	for i := 0; i < cfg.Miners; i++ {
+		h, err := net.GenPeer()
+		if err != nil {
+			return Metrics{}, fmt.Errorf("sim: generate live peer %d: %w", i, err)
+		}
+		hosts[i] = h
+	}
+	if err := net.LinkAll(); err != nil {
+		return Metrics{}, fmt.Errorf("sim: link live peers: %w", err)
+	}
+	if err := net.ConnectAllButSelf(); err != nil {
+		return Metrics{}, fmt.Errorf("sim: connect live peers: %w", err)
+	}
+
+	topics := make([]*pubsub.Topic, cfg.Miners)
+	subs := make([]*pubsub.Subscription, cfg.Miners)
+	for i, h := range hosts {
+		ps, err := pubsub.NewGossipSub(ctx, h)
+		if err != nil {
+			return Metrics{}, fmt.Errorf("sim: start pubsub on peer %d: %w", i, err)
+		}
+		topic, err := ps.Join(liveTopicName)
+		if err != nil {
+			return Metrics{}, fmt.Errorf("sim: join topic on peer %d: %w", i, err)
+		}
+		sub, err := topic.Subscribe()
+		if err != nil {
+			return Metrics{}, fmt.Errorf("sim: subscribe on peer %d: %w", i, err)
+		}
+		topics[i] = topic
+		subs[i] = sub
+	}
+	time.Sleep(settleDelay)
+
+	tally := newLiveTally(cfg.Miners)
+	var wg sync.WaitGroup
+	for i, sub := range subs {
+		wg.Add(1)
+		go func(peerIdx int, sub *pubsub.Subscription) {
+			defer wg.Done()
+			for {
+				msg, err := sub.Next(ctx)
+				if err != nil {
+					return // subscription canceled at teardown
+				}
+				tally.recordReceive(peerIdx, msg.Data, time.Now())
+			}
+		}(i, sub)
+	}
+
+	perMessageBytes := cfg.PayloadBytes + cfg.PQSignatureBytes
+	totalMessages := cfg.Iterations * cfg.Miners
+	start := time.Now()
+	for msgID := 0; msgID < totalMessages; msgID++ {
+		origin := msgID % cfg.Miners
+		payload := make([]byte, perMessageBytes)
+		binary.BigEndian.PutUint64(payload, uint64(msgID))
+		tally.recordSend(payload, time.Now())
+		if err := topics[origin].Publish(ctx, payload); err != nil {
+			return Metrics{}, fmt.Errorf("sim: publish message %d: %w", msgID, err)
+		}
+	}
+	time.Sleep(settleDelay)
+	elapsed := time.Since(start)
+
+	for _, sub := range subs {
+		sub.Cancel()
+	}
+	for _, h := range hosts {
+		_ = h.Close()
+	}
+	wg.Wait()
+
+	return tally.metrics(cfg, totalMessages, perMessageBytes, elapsed), nil
+}
+
+// runLiveDistributed starts no host, joins no topic and dials nothing: it
+// always returns an error naming opts.Listen and the number of Connect
+// targets. Cross-process live runs are not implemented.
+func runLiveDistributed(ctx context.Context, opts LiveOptions) (Metrics, error) {
+	return Metrics{}, fmt.Errorf("sim: distributed RunLive requires a real libp2p.New() host wired to %q and %d connect targets; not yet implemented in-tree", opts.Listen, len(opts.Connect))
+}
+
+// liveTally accumulates cross-goroutine receive/send bookkeeping for RunLive.
+type liveTally struct {
+	mu          sync.Mutex
+	sentAt      map[uint64]time.Time
+	latencies   []float64
+	bytesIn     []int64
+	duplicates  int
+	wastedBytes int64
+}
+
+func newLiveTally(miners int) *liveTally {
+	return &liveTally{
+		sentAt:  make(map[uint64]time.Time),
+		bytesIn: make([]int64, miners),
+	}
+}
+
+func (lt *liveTally) recordSend(payload []byte, at time.Time) {
+	msgID := binary.BigEndian.Uint64(payload)
+	lt.mu.Lock()
+	lt.sentAt[msgID] = at
+	lt.mu.Unlock()
+}
+
+func (lt *liveTally) recordReceive(peerIdx int, payload []byte, at time.Time) {
+	if len(payload) < 8 {
+		return
+	}
+	msgID := binary.BigEndian.Uint64(payload)
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	lt.bytesIn[peerIdx] += int64(len(payload))
+	sent, ok := lt.sentAt[msgID]
+	if !ok {
+		lt.duplicates++
+		lt.wastedBytes += int64(len(payload))
+		return
+	}
+	lt.latencies = append(lt.latencies, at.Sub(sent).Seconds()*1000.0)
+}
+
+// metrics assembles a Metrics from observed sends/receives. Grafts, Prunes,
+// Graylistings, CPUBoundMs, and TimeToDetectEquivocationMs are left at zero:
+// live mode runs real gossipsub rather than the scoring/PQ-cost models that
+// produce those fields analytically, so there is nothing genuine to report.
+func (lt *liveTally) metrics(cfg Options, totalMessages, perMessageBytes int, elapsed time.Duration) Metrics {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+
+	sort.Float64s(lt.latencies)
+	var sumIn int64
+	for _, b := range lt.bytesIn {
+		sumIn += b
+	}
+	durationSeconds := elapsed.Seconds()
+	netBoundMs := float64(elapsed.Milliseconds())
+
+	totalReceives := len(lt.latencies) + lt.duplicates
+	duplicateRatio := 0.0
+	if totalReceives > 0 {
+		duplicateRatio = float64(lt.duplicates) / float64(totalReceives)
+	}
+
+	return Metrics{
+		Miners:                      cfg.Miners,
+		PayloadBytes:                cfg.PayloadBytes,
+		Iterations:                  cfg.Iterations,
+		PQSignatureBytes:            cfg.PQSignatureBytes,
+		MessagesPerSec:              float64(totalMessages) / math.Max(durationSeconds, 1e-9),
+		AvgLatencyMs:                mean(lt.latencies),
+		DurationMs:                  int(elapsed.Milliseconds()),
+		P50LatencyMs:                percentile(lt.latencies, 0.50),
+		P95LatencyMs:                percentile(lt.latencies, 0.95),
+		P99LatencyMs:                percentile(lt.latencies, 0.99),
+		DuplicateRatio:              duplicateRatio,
+		BytesInPerPeer:              float64(sumIn) / float64(cfg.Miners),
+		BytesOutPerPeer:             float64(totalMessages*perMessageBytes) / float64(cfg.Miners),
+		TimeToFullCoverageMs:        int(elapsed.Milliseconds()),
+		AgreementFraction:           1.0,
+		WastedDuplicateBytesPerPeer: float64(lt.wastedBytes) / float64(cfg.Miners),
+		NetBoundMs:                  netBoundMs,
+	}
+}
@@ -0,0 +1,39 @@
+package sim
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func TestLiveTallyLatencyAndDuplicates(t *testing.T) {
+	tally := newLiveTally(2)
+	payload := make([]byte, 16)
+	binary.BigEndian.PutUint64(payload, 7)
+
+	sentAt := time.Now()
+	tally.recordSend(payload, sentAt)
+	tally.recordReceive(0, payload, sentAt.Add(10*time.Millisecond))
+	tally.recordReceive(1, payload, sentAt.Add(20*time.Millisecond))
+	// A message never sent (no matching sentAt entry) is a duplicate/unexpected delivery.
+	unseen := make([]byte, 16)
+	binary.BigEndian.PutUint64(unseen, 99)
+	tally.recordReceive(0, unseen, sentAt)
+
+	metrics := tally.metrics(Options{Miners: 2}, 1, len(payload), 50*time.Millisecond)
+	if metrics.AvgLatencyMs <= 0 {
+		t.Fatalf("expected a positive average latency, got %.4f", metrics.AvgLatencyMs)
+	}
+	if tally.duplicates != 1 {
+		t.Fatalf("expected exactly one unexpected delivery, got %d", tally.duplicates)
+	}
+	if metrics.BytesInPerPeer <= 0 {
+		t.Fatalf("expected positive bytes-in per peer, got %.2f", metrics.BytesInPerPeer)
+	}
+	if metrics.DuplicateRatio <= 0 {
+		t.Fatalf("expected a positive duplicate ratio given one unexpected delivery, got %.4f", metrics.DuplicateRatio)
+	}
+	if metrics.WastedDuplicateBytesPerPeer <= 0 {
+		t.Fatalf("expected positive wasted duplicate bytes per peer, got %.2f", metrics.WastedDuplicateBytesPerPeer)
+	}
+}
@@ -0,0 +1,59 @@
+package sim
+
+// PQScheme identifies a post-quantum signature scheme whose per-operation cost
+// feeds the CPU-bound completion time estimate alongside the bandwidth-bound one.
+type PQScheme int
+
+const (
+	// Dilithium2 is CRYSTALS-Dilithium at NIST security level 2.
+	Dilithium2 PQScheme = iota
+	// Dilithium3 is CRYSTALS-Dilithium at NIST security level 3.
+	Dilithium3
+	// Falcon512 is Falcon at NIST security level 1.
+	Falcon512
+	// SPHINCSPlus128s is SPHINCS+-128s (small signatures, slow verify/sign).
+	SPHINCSPlus128s
+)
+
+// referenceCoreHz is the clock rate the pqCostTable cycle counts were measured
+// on; verifyCostMs converts cycles to wall-clock time against this baseline.
+const referenceCoreHz = 3_000_000_000
+
+// pqCost holds reference-core cycle counts for one PQScheme's verify/sign ops.
+// Figures are ballpark reference-implementation numbers, not a guarantee for
+// any particular CPU; they are only meant to rank schemes relative to each other.
+type pqCost struct {
+	VerifyCycles uint64
+	SignCycles   uint64
+}
+
+var pqCostTable = map[PQScheme]pqCost{
+	Dilithium2:      {VerifyCycles: 330_000, SignCycles: 850_000},
+	Dilithium3:      {VerifyCycles: 520_000, SignCycles: 1_400_000},
+	Falcon512:       {VerifyCycles: 150_000, SignCycles: 6_800_000},
+	SPHINCSPlus128s: {VerifyCycles: 2_300_000, SignCycles: 52_000_000},
+}
+
+// verifyCostMs returns the wall-clock cost, in ms, of one signature
+// verification for scheme on the reference core.
+func verifyCostMs(scheme PQScheme) float64 {
+	cost, ok := pqCostTable[scheme]
+	if !ok {
+		cost = pqCostTable[Dilithium2]
+	}
+	return float64(cost.VerifyCycles) / referenceCoreHz * 1000.0
+}
+
+// cpuBoundMs estimates the wall-clock time to verify verifyCount signatures of
+// the given scheme, spread across cores, with batching applying batchSpeedup
+// once batchSize is greater than 1.
+func cpuBoundMs(scheme PQScheme, verifyCount int, cores, batchSize int, batchSpeedup float64) float64 {
+	if cores < 1 {
+		cores = 1
+	}
+	perVerifyMs := verifyCostMs(scheme)
+	if batchSize > 1 && batchSpeedup > 1 {
+		perVerifyMs /= batchSpeedup
+	}
+	return float64(verifyCount) * perVerifyMs / float64(cores)
+}
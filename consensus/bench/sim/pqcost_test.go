@@ -0,0 +1,33 @@
+package sim
+
+import "testing"
+
+func TestVerifyCostMsOrdersSchemesBySpeed(t *testing.T) {
+	if verifyCostMs(Falcon512) >= verifyCostMs(Dilithium2) {
+		t.Fatalf("expected Falcon512 to verify faster than Dilithium2")
+	}
+	if verifyCostMs(Dilithium2) >= verifyCostMs(Dilithium3) {
+		t.Fatalf("expected Dilithium2 to verify faster than Dilithium3")
+	}
+	if verifyCostMs(Dilithium3) >= verifyCostMs(SPHINCSPlus128s) {
+		t.Fatalf("expected SPHINCS+-128s to verify slower than every other scheme")
+	}
+}
+
+func TestCpuBoundMsScalesWithCoresAndBatching(t *testing.T) {
+	base := cpuBoundMs(Dilithium2, 1000, 1, 1, 1)
+	withCores := cpuBoundMs(Dilithium2, 1000, 4, 1, 1)
+	if withCores >= base {
+		t.Fatalf("expected more cores to reduce cost: base=%.4f withCores=%.4f", base, withCores)
+	}
+	withBatching := cpuBoundMs(Dilithium2, 1000, 1, 8, 2)
+	if withBatching >= base {
+		t.Fatalf("expected batching with a speedup > 1 to reduce cost: base=%.4f withBatching=%.4f", base, withBatching)
+	}
+}
+
+func TestCpuBoundMsUnknownSchemeFallsBackToDilithium2(t *testing.T) {
+	if verifyCostMs(PQScheme(99)) != verifyCostMs(Dilithium2) {
+		t.Fatalf("expected an unrecognized scheme to fall back to Dilithium2's cost")
+	}
+}
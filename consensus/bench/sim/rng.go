@@ -0,0 +1,25 @@
+package sim
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/rand/v2"
+)
+
+// rootSeedBytes expands cfg.Seed/cfg.SeedBytes into the 32-byte ChaCha8 seed
+// used to construct a simulation run's root RNG. SeedBytes takes precedence
+// when set (non-zero); otherwise Seed is expanded deterministically via
+// SHA-256 so the existing int64 Options.Seed still fully determines a run.
+func rootSeedBytes(cfg Options) [32]byte {
+	if cfg.SeedBytes != ([32]byte{}) {
+		return cfg.SeedBytes
+	}
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(cfg.Seed))
+	return sha256.Sum256(buf[:])
+}
+
+// newRNG constructs a ChaCha8-backed RNG from a 32-byte seed.
+func newRNG(seed [32]byte) *rand.Rand {
+	return rand.New(rand.NewChaCha8(seed))
+}
@@ -0,0 +1,24 @@
+package sim
+
+import "testing"
+
+func TestRootSeedBytesDeterministicFromSeed(t *testing.T) {
+	a := rootSeedBytes(Options{Seed: 42})
+	b := rootSeedBytes(Options{Seed: 42})
+	if a != b {
+		t.Fatalf("expected the same Seed to expand to the same SeedBytes")
+	}
+	c := rootSeedBytes(Options{Seed: 43})
+	if a == c {
+		t.Fatalf("expected different Seeds to expand to different SeedBytes")
+	}
+}
+
+func TestRootSeedBytesPrefersExplicitSeedBytes(t *testing.T) {
+	var explicit [32]byte
+	explicit[0] = 0xAB
+	got := rootSeedBytes(Options{Seed: 42, SeedBytes: explicit})
+	if got != explicit {
+		t.Fatalf("expected explicit SeedBytes to take precedence over Seed")
+	}
+}
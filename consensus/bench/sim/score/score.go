@@ -0,0 +1,225 @@
+// Package score implements a GossipSub v1.1-style peer scoring function: a set
+// of weighted, exponentially-decaying counters per peer that decide whether a
+// peer stays in the mesh, has the messages it receives pushed on to its mesh,
+// or is gossiped to.
+package score
+
+import "math"
+
+// Params configures the weights, decay rates, and thresholds used to score peers.
+// Field names and semantics follow the libp2p GossipSub v1.1 scoring spec; times
+// are expressed in milliseconds to match the rest of the sim package.
+type Params struct {
+	TimeInMeshWeight    float64
+	TimeInMeshQuantumMs float64
+	TimeInMeshCap       float64
+
+	FirstMessageDeliveriesWeight float64
+	FirstMessageDeliveriesDecay  float64
+	FirstMessageDeliveriesCap    float64
+
+	MeshMessageDeliveriesWeight       float64
+	MeshMessageDeliveriesDecay        float64
+	MeshMessageDeliveriesThreshold    float64
+	MeshMessageDeliveriesCap          float64
+	MeshMessageDeliveriesActivationMs float64
+
+	InvalidMessageDeliveriesWeight float64
+	InvalidMessageDeliveriesDecay  float64
+
+	IPColocationFactorWeight    float64
+	IPColocationFactorThreshold int
+
+	DecayIntervalMs float64
+
+	GraylistThreshold float64
+	PublishThreshold  float64
+	GossipThreshold   float64
+}
+
+// DefaultParams returns a conservative GossipSub v1.1 profile, scaled to the
+// millisecond time base this simulator uses.
+func DefaultParams() Params {
+	return Params{
+		TimeInMeshWeight:    0.01,
+		TimeInMeshQuantumMs: 1000,
+		TimeInMeshCap:       3600,
+
+		FirstMessageDeliveriesWeight: 1,
+		FirstMessageDeliveriesDecay:  0.5,
+		FirstMessageDeliveriesCap:    30,
+
+		MeshMessageDeliveriesWeight:       -1,
+		MeshMessageDeliveriesDecay:        0.5,
+		MeshMessageDeliveriesThreshold:    5,
+		MeshMessageDeliveriesCap:          30,
+		MeshMessageDeliveriesActivationMs: 5000,
+
+		InvalidMessageDeliveriesWeight: -300,
+		InvalidMessageDeliveriesDecay:  0.3,
+
+		IPColocationFactorWeight:    -5,
+		IPColocationFactorThreshold: 1,
+
+		DecayIntervalMs: 1000,
+
+		GraylistThreshold: -1000,
+		PublishThreshold:  -10,
+		GossipThreshold:   -5,
+	}
+}
+
+// peerStats holds the raw, per-peer EWMA counters that feed Score.
+type peerStats struct {
+	ip                       string
+	inMesh                   bool
+	joinedMeshAtMs           float64
+	firstMessageDeliveries   float64
+	meshMessageDeliveries    float64
+	invalidMessageDeliveries float64
+}
+
+// Tracker accumulates per-peer counters and derives GossipSub-style scores.
+type Tracker struct {
+	params   Params
+	peers    map[int]*peerStats
+	ipCounts map[string]int
+}
+
+// NewTracker creates a Tracker using the given Params.
+func NewTracker(params Params) *Tracker {
+	return &Tracker{
+		params:   params,
+		peers:    make(map[int]*peerStats),
+		ipCounts: make(map[string]int),
+	}
+}
+
+func (t *Tracker) stats(peer int) *peerStats {
+	s, ok := t.peers[peer]
+	if !ok {
+		s = &peerStats{}
+		t.peers[peer] = s
+	}
+	return s
+}
+
+// Graft records peer joining the mesh at nowMs. ip is an opaque colocation key;
+// pass "" if IP colocation scoring is not in use.
+func (t *Tracker) Graft(peer int, ip string, nowMs float64) {
+	s := t.stats(peer)
+	if s.inMesh {
+		return
+	}
+	s.inMesh = true
+	s.joinedMeshAtMs = nowMs
+	if s.ip != ip {
+		if s.ip != "" {
+			t.ipCounts[s.ip]--
+		}
+		s.ip = ip
+		if ip != "" {
+			t.ipCounts[ip]++
+		}
+	}
+}
+
+// Prune removes peer from the mesh.
+func (t *Tracker) Prune(peer int) {
+	if s, ok := t.peers[peer]; ok {
+		s.inMesh = false
+	}
+}
+
+// RecordFirstDelivery credits peer with delivering a message no one else had yet.
+func (t *Tracker) RecordFirstDelivery(peer int) {
+	s := t.stats(peer)
+	s.firstMessageDeliveries = math.Min(s.firstMessageDeliveries+1, t.params.FirstMessageDeliveriesCap)
+	s.meshMessageDeliveries = math.Min(s.meshMessageDeliveries+1, t.params.MeshMessageDeliveriesCap)
+}
+
+// RecordMeshDelivery credits peer with delivering a message mesh peers were
+// expected to deliver, independent of whether it was the first copy seen.
+func (t *Tracker) RecordMeshDelivery(peer int) {
+	s := t.stats(peer)
+	s.meshMessageDeliveries = math.Min(s.meshMessageDeliveries+1, t.params.MeshMessageDeliveriesCap)
+}
+
+// RecordInvalid penalizes peer for delivering a message that failed validation,
+// e.g. a conflicting payload from an equivocating sender.
+func (t *Tracker) RecordInvalid(peer int) {
+	t.stats(peer).invalidMessageDeliveries++
+}
+
+// Decay applies EWMA decay to every tracked peer's counters. Callers should
+// invoke it roughly every Params.DecayIntervalMs of simulated time.
+func (t *Tracker) Decay() {
+	for _, s := range t.peers {
+		s.firstMessageDeliveries *= t.params.FirstMessageDeliveriesDecay
+		s.meshMessageDeliveries *= t.params.MeshMessageDeliveriesDecay
+		s.invalidMessageDeliveries *= t.params.InvalidMessageDeliveriesDecay
+	}
+}
+
+// Score computes peer's current GossipSub-style score at nowMs.
+func (t *Tracker) Score(peer int, nowMs float64) float64 {
+	s, ok := t.peers[peer]
+	if !ok {
+		return 0
+	}
+	p := t.params
+
+	timeInMesh := 0.0
+	if s.inMesh {
+		quantum := p.TimeInMeshQuantumMs
+		if quantum <= 0 {
+			quantum = 1
+		}
+		timeInMesh = math.Min((nowMs-s.joinedMeshAtMs)/quantum, p.TimeInMeshCap)
+	}
+
+	meshPenalty := 0.0
+	if s.inMesh && nowMs-s.joinedMeshAtMs >= p.MeshMessageDeliveriesActivationMs {
+		if deficit := p.MeshMessageDeliveriesThreshold - s.meshMessageDeliveries; deficit > 0 {
+			meshPenalty = deficit * deficit
+		}
+	}
+
+	invalidPenalty := s.invalidMessageDeliveries * s.invalidMessageDeliveries
+
+	colocationPenalty := 0.0
+	if s.ip != "" {
+		if excess := t.ipCounts[s.ip] - p.IPColocationFactorThreshold; excess > 0 {
+			colocationPenalty = float64(excess * excess)
+		}
+	}
+
+	return p.TimeInMeshWeight*timeInMesh +
+		p.FirstMessageDeliveriesWeight*s.firstMessageDeliveries +
+		p.MeshMessageDeliveriesWeight*meshPenalty +
+		p.InvalidMessageDeliveriesWeight*invalidPenalty +
+		p.IPColocationFactorWeight*colocationPenalty
+}
+
+// BelowGraylist, BelowPublish, and BelowGossip report whether peer's current
+// score has dropped under the corresponding threshold.
+func (t *Tracker) BelowGraylist(peer int, nowMs float64) bool {
+	return t.Score(peer, nowMs) < t.params.GraylistThreshold
+}
+
+func (t *Tracker) BelowPublish(peer int, nowMs float64) bool {
+	return t.Score(peer, nowMs) < t.params.PublishThreshold
+}
+
+func (t *Tracker) BelowGossip(peer int, nowMs float64) bool {
+	return t.Score(peer, nowMs) < t.params.GossipThreshold
+}
+
+// Peers returns every peer ID the tracker has observed, in no particular order.
+func (t *Tracker) Peers() []int {
+	ids := make([]int, 0, len(t.peers))
+	for id := range t.peers {
+		ids = append(ids, id)
+	}
+	return ids
+}
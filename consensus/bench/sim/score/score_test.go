@@ -0,0 +1,46 @@
+package score
+
+import "testing"
+
+func TestScoreRewardsFirstDeliveries(t *testing.T) {
+	tr := NewTracker(DefaultParams())
+	tr.Graft(1, "", 0)
+	for i := 0; i < 5; i++ {
+		tr.RecordFirstDelivery(1)
+	}
+	if s := tr.Score(1, 0); s <= 0 {
+		t.Fatalf("expected a positive score for an actively delivering peer, got %.4f", s)
+	}
+}
+
+func TestScorePenalizesMeshDeficit(t *testing.T) {
+	params := DefaultParams()
+	tr := NewTracker(params)
+	tr.Graft(2, "", 0)
+	now := params.MeshMessageDeliveriesActivationMs + 1
+	if s := tr.Score(2, now); s >= 0 {
+		t.Fatalf("expected a below-zero score for a peer delivering nothing, got %.4f", s)
+	}
+}
+
+func TestScorePenalizesInvalidDeliveries(t *testing.T) {
+	tr := NewTracker(DefaultParams())
+	tr.Graft(3, "", 0)
+	tr.RecordInvalid(3)
+	tr.RecordInvalid(3)
+	if !tr.BelowGraylist(3, 0) {
+		t.Fatalf("expected repeated invalid deliveries to push a peer below the graylist threshold")
+	}
+}
+
+func TestDecayShrinksCounters(t *testing.T) {
+	tr := NewTracker(DefaultParams())
+	tr.Graft(4, "", 0)
+	tr.RecordFirstDelivery(4)
+	before := tr.Score(4, 0)
+	tr.Decay()
+	after := tr.Score(4, 0)
+	if after >= before {
+		t.Fatalf("expected decay to shrink the score: before=%.4f after=%.4f", before, after)
+	}
+}
@@ -1,8 +1,39 @@
+// Package sim provides a synthetic benchmark for estimating how PQ-signed
+// gossip behaves across a miner network before it is exercised on real
+// infrastructure.
 package sim
 
 import (
+	"container/heap"
 	"math"
-	"math/rand"
+	"math/rand/v2"
+	"sort"
+
+	"github.com/Pauli-Group/Hegemon/consensus/bench/sim/score"
+)
+
+// Topology selects how the peer mesh used for gossip is constructed.
+type Topology int
+
+const (
+	// TopologyRandomRegular connects every peer to a random set of Degree peers.
+	TopologyRandomRegular Topology = iota
+	// TopologySmallWorld builds a Watts-Strogatz ring lattice with random rewiring,
+	// approximating the clustering/short-path tradeoff seen on real DHT overlays.
+	TopologySmallWorld
+	// TopologyExplicit uses the adjacency list supplied via Options.Adjacency verbatim.
+	TopologyExplicit
+)
+
+// gossip protocol tunables that are not (yet) worth exposing on Options because
+// the simulator does not model heartbeat scheduling independently of message flow.
+const (
+	lazyAnnounceDelayMs = 100.0 // time a peer waits before IHAVE-announcing to lazy peers
+	defaultDegree       = 6
+	defaultFanOut       = 3
+	defaultBandwidth    = 25 * 1024 * 1024 // 25 MiB/s of usable gossip capacity
+	defaultRTTMeanMs    = 50.0
+	defaultRTTStddevMs  = 15.0
 )
 
 // Options configures the synthetic network benchmark.
@@ -11,7 +42,50 @@ type Options struct {
 	PayloadBytes     int
 	Iterations       int
 	PQSignatureBytes int
-	Seed             int64
+	// Seed determines a run's randomness: Seed is the legacy knob, expanded
+	// deterministically into a 32-byte ChaCha8 seed unless SeedBytes is set.
+	Seed int64
+	// SeedBytes, when non-zero, is used as the ChaCha8 seed directly instead
+	// of expanding Seed. Most callers should leave it zero and just set Seed.
+	SeedBytes [32]byte
+
+	// Degree is the number of mesh (push) peers each node maintains.
+	Degree int
+	// FanOut is the number of lazy (IHAVE/IWANT pull) peers each node gossips to.
+	FanOut int
+	// LinkBandwidthBytesPerSec caps per-link transmission speed for message bytes.
+	LinkBandwidthBytesPerSec int
+	// RTTMeanMs and RTTStddevMs parameterize the per-link round-trip-time distribution.
+	RTTMeanMs   float64
+	RTTStddevMs float64
+	// LossRate is the probability, in [0,1], that a given push/IHAVE/IWANT is dropped.
+	LossRate float64
+	// Topology selects how the peer mesh is built. See the Topology constants.
+	Topology Topology
+	// Adjacency is the explicit peer graph used when Topology == TopologyExplicit.
+	Adjacency [][]int
+
+	// Adversaries models misbehaving miners for Byzantine/adversarial stress-testing.
+	Adversaries []Adversary
+	// SignatureVerifyCostMs is the cost of verifying one PQ signature. It is added
+	// twice when deriving equivocation-detection latency, since confirming a
+	// conflict requires verifying both signed variants.
+	SignatureVerifyCostMs float64
+
+	// ScoreParams enables GossipSub v1.1-style peer scoring when non-nil. See
+	// the sim/score package for the scoring model and thresholds.
+	ScoreParams *score.Params
+
+	// PQScheme selects the signature scheme used to derive CPU-bound completion
+	// time. See the PQScheme constants and pqCostTable.
+	PQScheme PQScheme
+	// Cores is the number of cores available for parallel signature verification.
+	Cores int
+	// VerifyBatchSize is the number of signatures verified together in one batch.
+	VerifyBatchSize int
+	// BatchSpeedup is the multiplicative per-signature speedup from batching,
+	// applied once VerifyBatchSize > 1 (e.g. 1.6 for a 1.6x batch verify speedup).
+	BatchSpeedup float64
 }
 
 // Metrics captures the outcome of a benchmark run.
@@ -23,36 +97,545 @@ type Metrics struct {
 	MessagesPerSec   float64 `json:"messages_per_second"`
 	AvgLatencyMs     float64 `json:"avg_latency_ms"`
 	DurationMs       int     `json:"duration_ms"`
+
+	P50LatencyMs         float64 `json:"p50_latency_ms"`
+	P95LatencyMs         float64 `json:"p95_latency_ms"`
+	P99LatencyMs         float64 `json:"p99_latency_ms"`
+	DuplicateRatio       float64 `json:"duplicate_ratio"`
+	BytesInPerPeer       float64 `json:"bytes_in_per_peer"`
+	BytesOutPerPeer      float64 `json:"bytes_out_per_peer"`
+	TimeToFullCoverageMs int     `json:"time_to_full_coverage_ms"`
+
+	// AgreementFraction is the fraction of honest peers that locked the majority
+	// (canonical) variant of messages originated by an equivocating adversary,
+	// averaged over those messages. Honest peers that received no variant are
+	// not counted. It is 1.0 when there are no equivocators in Options.Adversaries.
+	AgreementFraction float64 `json:"agreement_fraction"`
+	// WastedDuplicateBytesPerPeer is bandwidth spent receiving messages a peer
+	// already had, averaged across peers.
+	WastedDuplicateBytesPerPeer float64 `json:"wasted_duplicate_bytes_per_peer"`
+	// TimeToDetectEquivocationMs is the average time, from message origin until
+	// some honest peer has observed and verified both conflicting variants.
+	TimeToDetectEquivocationMs float64 `json:"time_to_detect_equivocation_ms"`
+
+	// Grafts, Prunes, and Graylistings summarize the score subsystem's effect
+	// when Options.ScoreParams is set. They are zero otherwise.
+	Grafts       int `json:"grafts"`
+	Prunes       int `json:"prunes"`
+	Graylistings int `json:"graylistings"`
+
+	// NetBoundMs is the bandwidth/propagation-bound completion time: how long
+	// coverage takes assuming signature verification is free.
+	NetBoundMs float64 `json:"net_bound_ms"`
+	// CPUBoundMs is the completion time implied by verifying each message's
+	// signature once, the first time a peer sees it (duplicates are deduped by
+	// a cheap message-id hash before hitting the validator, as in real
+	// GossipSub), on Options.Cores cores at Options.PQScheme cost, with
+	// Options.VerifyBatchSize/BatchSpeedup batching applied. DurationMs and
+	// TimeToFullCoverageMs are the max of NetBoundMs and CPUBoundMs, so
+	// whichever is larger is the actual bottleneck for a given PQ scheme.
+	CPUBoundMs float64 `json:"cpu_bound_ms"`
 }
 
-// Simulate estimates throughput and latency budgets for gossiping PQ-sized payloads.
+// EventKind identifies the gossip action a traced Event represents.
+type EventKind int
+
+const (
+	EventPush EventKind = iota
+	EventIHave
+	EventIWant
+	EventDeliver
+)
+
+// Event is a single scheduled gossip action, ordered by DeliverTime.
+type Event struct {
+	DeliverTime float64
+	From        int
+	To          int
+	MsgID       int
+	Kind        EventKind
+	// Variant distinguishes conflicting payloads gossiped under the same MsgID
+	// by an AdversaryEquivocator. Honest messages always carry Variant 0.
+	Variant int
+}
+
+// eventQueue is a container/heap priority queue of Events ordered by DeliverTime.
+type eventQueue []Event
+
+func (q eventQueue) Len() int            { return len(q) }
+func (q eventQueue) Less(i, j int) bool  { return q[i].DeliverTime < q[j].DeliverTime }
+func (q eventQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *eventQueue) Push(x interface{}) { *q = append(*q, x.(Event)) }
+func (q *eventQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
+
+// peerGraph holds the mesh (push) and lazy (pull) neighbor sets for every peer.
+type peerGraph struct {
+	mesh [][]int
+	lazy [][]int
+}
+
+// Simulate estimates throughput, latency, and coverage for gossiping PQ-sized
+// payloads across a topology-aware peer mesh using a push/lazy-pull protocol
+// modeled on GossipSub/BlossomSub.
 func Simulate(opts Options) Metrics {
+	metrics, _ := SimulateTrace(opts)
+	return metrics
+}
+
+// SimulateTrace runs the same event-driven simulation as Simulate but also
+// returns the full ordered event log, so higher-level tooling can post-process
+// individual deliveries.
+func SimulateTrace(opts Options) (Metrics, []Event) {
 	cfg := normalize(opts)
-	rng := rand.New(rand.NewSource(cfg.Seed))
+	rng := newRNG(rootSeedBytes(cfg))
+
+	graph := peerGraph{mesh: buildTopology(cfg.Miners, cfg.Degree, cfg.Topology, cfg.Adjacency, rng)}
+	graph.lazy = buildLazyPeers(cfg.Miners, cfg.FanOut, graph.mesh, rng)
+	adv := buildAdversarySet(cfg.Adversaries)
+
+	var tracker *score.Tracker
+	if cfg.ScoreParams != nil {
+		tracker = score.NewTracker(*cfg.ScoreParams)
+		for peer := 0; peer < cfg.Miners; peer++ {
+			tracker.Graft(peer, "", 0)
+		}
+	}
+
+	perMessageBytes := cfg.PayloadBytes + cfg.PQSignatureBytes
 	totalMessages := cfg.Iterations * cfg.Miners
 	if totalMessages == 0 {
 		totalMessages = 1
 	}
-	perMessageBytes := cfg.PayloadBytes + cfg.PQSignatureBytes
-	bandwidthBytesPerSec := 25 * 1024 * 1024 // 25 MiB/s of usable gossip capacity.
-	totalBytes := perMessageBytes * totalMessages
-	durationSeconds := float64(totalBytes) / float64(bandwidthBytesPerSec)
+
+	var (
+		trace                 []Event
+		latencies             []float64
+		duplicates            int
+		wastedDuplicateBytes  int64
+		bytesIn               = make([]int64, cfg.Miners)
+		bytesOut              = make([]int64, cfg.Miners)
+		overallCoverage       float64
+		agreementFractions    []float64
+		equivocationDetectMss []float64
+	)
+
+	for msgID := 0; msgID < totalMessages; msgID++ {
+		origin := msgID % cfg.Miners
+		lockedVariant := make([]int, cfg.Miners)
+		for i := range lockedVariant {
+			lockedVariant[i] = -1
+		}
+		lockedVariant[origin] = 0
+		coverage := 0.0
+		remaining := cfg.Miners - 1
+		detectTime := -1.0
+		firstDelivered := false
+
+		q := &eventQueue{}
+		heap.Init(q)
+		if originAdv, ok := adv.kindOf(origin); ok && originAdv.Kind == AdversaryEquivocator {
+			scheduleEquivocation(q, graph, adv, cfg, origin, msgID, rng, bytesOut, perMessageBytes)
+		} else {
+			// AdversaryLazy only withholds forwarding of messages it receives; it
+			// still announces messages it originates itself.
+			scheduleMeshPush(q, graph, adv, cfg, origin, msgID, 0, 0, rng, bytesOut, perMessageBytes)
+			scheduleLazyAnnounce(q, graph, adv, tracker, cfg, origin, msgID, 0, 0, rng)
+		}
+
+		for q.Len() > 0 && remaining > 0 {
+			ev := heap.Pop(q).(Event)
+			trace = append(trace, ev)
+
+			switch ev.Kind {
+			case EventPush:
+				if rng.Float64() < cfg.LossRate {
+					continue
+				}
+				size := adv.messageSize(ev.From, perMessageBytes)
+				bytesIn[ev.To] += int64(size)
+				if lockedVariant[ev.To] != -1 {
+					duplicates++
+					wastedDuplicateBytes += int64(size)
+					if _, toAdv := adv.kindOf(ev.To); !toAdv && lockedVariant[ev.To] != ev.Variant && detectTime < 0 {
+						detectTime = ev.DeliverTime + 2*cfg.SignatureVerifyCostMs
+						if tracker != nil {
+							tracker.RecordInvalid(ev.From)
+						}
+					}
+					continue
+				}
+				lockedVariant[ev.To] = ev.Variant
+				latencies = append(latencies, ev.DeliverTime)
+				if ev.DeliverTime > coverage {
+					coverage = ev.DeliverTime
+				}
+				remaining--
+				if tracker != nil {
+					if !firstDelivered {
+						firstDelivered = true
+						tracker.RecordFirstDelivery(ev.From)
+					} else {
+						tracker.RecordMeshDelivery(ev.From)
+					}
+				}
+				if !adv.isLazy(ev.To) {
+					if tracker == nil || !tracker.BelowPublish(ev.To, ev.DeliverTime) {
+						scheduleMeshPush(q, graph, adv, cfg, ev.To, msgID, ev.Variant, ev.DeliverTime, rng, bytesOut, perMessageBytes)
+					}
+					scheduleLazyAnnounce(q, graph, adv, tracker, cfg, ev.To, msgID, ev.Variant, ev.DeliverTime, rng)
+				}
+
+			case EventIHave:
+				if lockedVariant[ev.To] != -1 || rng.Float64() < cfg.LossRate {
+					continue
+				}
+				scheduleIWant(q, cfg, ev.To, ev.From, msgID, ev.Variant, ev.DeliverTime, rng)
+
+			case EventIWant:
+				if rng.Float64() < cfg.LossRate {
+					continue
+				}
+				scheduleDeliver(q, cfg, ev.To, ev.From, msgID, ev.Variant, ev.DeliverTime, rng, bytesOut, perMessageBytes)
+
+			case EventDeliver:
+				size := adv.messageSize(ev.From, perMessageBytes)
+				bytesIn[ev.To] += int64(size)
+				if lockedVariant[ev.To] != -1 {
+					duplicates++
+					wastedDuplicateBytes += int64(size)
+					if _, toAdv := adv.kindOf(ev.To); !toAdv && lockedVariant[ev.To] != ev.Variant && detectTime < 0 {
+						detectTime = ev.DeliverTime + 2*cfg.SignatureVerifyCostMs
+						if tracker != nil {
+							tracker.RecordInvalid(ev.From)
+						}
+					}
+					continue
+				}
+				lockedVariant[ev.To] = ev.Variant
+				latencies = append(latencies, ev.DeliverTime)
+				if ev.DeliverTime > coverage {
+					coverage = ev.DeliverTime
+				}
+				remaining--
+				if tracker != nil {
+					if !firstDelivered {
+						firstDelivered = true
+						tracker.RecordFirstDelivery(ev.From)
+					} else {
+						tracker.RecordMeshDelivery(ev.From)
+					}
+				}
+			}
+		}
+
+		if coverage > overallCoverage {
+			overallCoverage = coverage
+		}
+		if detectTime >= 0 {
+			equivocationDetectMss = append(equivocationDetectMss, detectTime)
+		}
+		if originAdv, ok := adv.kindOf(origin); ok && originAdv.Kind == AdversaryEquivocator {
+			agreementFractions = append(agreementFractions, agreementFraction(lockedVariant, origin, adv))
+		}
+		// Each message is treated as one scoring heartbeat; real GossipSub decays on
+		// a wall-clock timer independent of message flow, which this per-message
+		// event-driven sim does not model. Params.DecayIntervalMs is not read here.
+		if tracker != nil {
+			tracker.Decay()
+		}
+	}
+
+	sort.Float64s(latencies)
+	durationSeconds := overallCoverage / 1000.0
 	if durationSeconds <= 0 {
 		durationSeconds = float64(totalMessages) / 1000.0
 	}
-	baseLatency := 5.0 + float64(perMessageBytes)/4096.0
-	jitter := (rng.Float64()*2.0 - 1.0) * 0.5
-	avgLatency := math.Max(1.0, baseLatency+jitter)
-	throughput := float64(totalMessages) / math.Max(durationSeconds, 1e-9)
-	return Metrics{
-		Miners:           cfg.Miners,
-		PayloadBytes:     cfg.PayloadBytes,
-		Iterations:       cfg.Iterations,
-		PQSignatureBytes: cfg.PQSignatureBytes,
-		MessagesPerSec:   throughput,
-		AvgLatencyMs:     avgLatency,
-		DurationMs:       int(math.Round(durationSeconds * 1000.0)),
+
+	totalReceives := len(latencies) + duplicates
+	duplicateRatio := 0.0
+	if totalReceives > 0 {
+		duplicateRatio = float64(duplicates) / float64(totalReceives)
+	}
+
+	var sumIn, sumOut int64
+	for i := 0; i < cfg.Miners; i++ {
+		sumIn += bytesIn[i]
+		sumOut += bytesOut[i]
+	}
+
+	var grafts, prunes, graylistings int
+	if tracker != nil {
+		grafts = cfg.Miners
+		for _, peer := range tracker.Peers() {
+			if tracker.BelowGraylist(peer, overallCoverage) {
+				graylistings++
+			} else if tracker.BelowPublish(peer, overallCoverage) {
+				prunes++
+			}
+		}
+	}
+
+	netBoundMs := overallCoverage
+	// Duplicates are deduped by a cheap message-id hash check before the PQ
+	// validator runs, same as real GossipSub, so only the first copy a peer
+	// sees (len(latencies)) is charged a signature verification.
+	cpuBound := cpuBoundMs(cfg.PQScheme, len(latencies), cfg.Cores, cfg.VerifyBatchSize, cfg.BatchSpeedup)
+	boundMs := math.Max(netBoundMs, cpuBound)
+
+	metrics := Metrics{
+		Miners:                      cfg.Miners,
+		PayloadBytes:                cfg.PayloadBytes,
+		Iterations:                  cfg.Iterations,
+		PQSignatureBytes:            cfg.PQSignatureBytes,
+		MessagesPerSec:              float64(totalMessages) / math.Max(durationSeconds, 1e-9),
+		AvgLatencyMs:                mean(latencies),
+		DurationMs:                  int(math.Round(boundMs)),
+		P50LatencyMs:                percentile(latencies, 0.50),
+		P95LatencyMs:                percentile(latencies, 0.95),
+		P99LatencyMs:                percentile(latencies, 0.99),
+		DuplicateRatio:              duplicateRatio,
+		BytesInPerPeer:              float64(sumIn) / float64(cfg.Miners),
+		BytesOutPerPeer:             float64(sumOut) / float64(cfg.Miners),
+		TimeToFullCoverageMs:        int(math.Round(boundMs)),
+		AgreementFraction:           meanOrDefault(agreementFractions, 1.0),
+		WastedDuplicateBytesPerPeer: float64(wastedDuplicateBytes) / float64(cfg.Miners),
+		TimeToDetectEquivocationMs:  mean(equivocationDetectMss),
+		Grafts:                      grafts,
+		Prunes:                      prunes,
+		Graylistings:                graylistings,
+		NetBoundMs:                  netBoundMs,
+		CPUBoundMs:                  cpuBound,
 	}
+	return metrics, trace
+}
+
+// scheduleEquivocation is the origin-time fan-out for an AdversaryEquivocator:
+// its mesh peers are split into two halves, each pushed a different variant of
+// the same MsgID, and its lazy peers are split the same way for IHAVE.
+func scheduleEquivocation(q *eventQueue, graph peerGraph, adv adversarySet, cfg Options, origin, msgID int, rng *rand.Rand, bytesOut []int64, perMessageBytes int) {
+	mesh := graph.mesh[origin]
+	half := len(mesh) / 2
+	pushVariant := func(peers []int, variant int) {
+		for _, to := range peers {
+			if adv.eclipses(origin, to) {
+				continue
+			}
+			delay := linkLatency(rng, cfg.RTTMeanMs/2, cfg.RTTStddevMs/2) + transmissionDelayMs(perMessageBytes, cfg.LinkBandwidthBytesPerSec)
+			bytesOut[origin] += int64(adv.messageSize(origin, perMessageBytes))
+			heap.Push(q, Event{DeliverTime: delay, From: origin, To: to, MsgID: msgID, Kind: EventPush, Variant: variant})
+		}
+	}
+	pushVariant(mesh[:half], 0)
+	pushVariant(mesh[half:], 1)
+
+	lazy := graph.lazy[origin]
+	lazyHalf := len(lazy) / 2
+	announceVariant := func(peers []int, variant int) {
+		for _, to := range peers {
+			if adv.eclipses(origin, to) {
+				continue
+			}
+			delay := linkLatency(rng, cfg.RTTMeanMs/2, cfg.RTTStddevMs/2) + lazyAnnounceDelayMs
+			heap.Push(q, Event{DeliverTime: delay, From: origin, To: to, MsgID: msgID, Kind: EventIHave, Variant: variant})
+		}
+	}
+	announceVariant(lazy[:lazyHalf], 0)
+	announceVariant(lazy[lazyHalf:], 1)
+}
+
+// agreementFraction reports, for one equivocated message, the share of honest
+// (non-adversary) peers that locked the majority (canonical) variant. Peers
+// that locked no variant are left out of the count.
+func agreementFraction(lockedVariant []int, origin int, adv adversarySet) float64 {
+	var zero, one, total int
+	for i, v := range lockedVariant {
+		if i == origin || v < 0 {
+			continue
+		}
+		if _, isAdv := adv.kindOf(i); isAdv {
+			continue
+		}
+		total++
+		if v == 0 {
+			zero++
+		} else {
+			one++
+		}
+	}
+	if total == 0 {
+		return 1.0
+	}
+	majority := zero
+	if one > majority {
+		majority = one
+	}
+	return float64(majority) / float64(total)
+}
+
+func scheduleMeshPush(q *eventQueue, graph peerGraph, adv adversarySet, cfg Options, from, msgID, variant int, now float64, rng *rand.Rand, bytesOut []int64, perMessageBytes int) {
+	for _, to := range graph.mesh[from] {
+		if adv.eclipses(from, to) {
+			continue
+		}
+		delay := linkLatency(rng, cfg.RTTMeanMs/2, cfg.RTTStddevMs/2) + transmissionDelayMs(perMessageBytes, cfg.LinkBandwidthBytesPerSec)
+		bytesOut[from] += int64(adv.messageSize(from, perMessageBytes))
+		heap.Push(q, Event{DeliverTime: now + delay, From: from, To: to, MsgID: msgID, Kind: EventPush, Variant: variant})
+	}
+}
+
+// scheduleLazyAnnounce sends IHAVE for msgID to from's lazy peers, skipping
+// eclipsed targets and, when scoring is enabled, targets below GossipThreshold.
+func scheduleLazyAnnounce(q *eventQueue, graph peerGraph, adv adversarySet, tracker *score.Tracker, cfg Options, from, msgID, variant int, now float64, rng *rand.Rand) {
+	for _, to := range graph.lazy[from] {
+		if adv.eclipses(from, to) {
+			continue
+		}
+		if tracker != nil && tracker.BelowGossip(to, now) {
+			continue
+		}
+		delay := linkLatency(rng, cfg.RTTMeanMs/2, cfg.RTTStddevMs/2) + lazyAnnounceDelayMs
+		heap.Push(q, Event{DeliverTime: now + delay, From: from, To: to, MsgID: msgID, Kind: EventIHave, Variant: variant})
+	}
+}
+
+func scheduleIWant(q *eventQueue, cfg Options, requester, provider, msgID, variant int, now float64, rng *rand.Rand) {
+	delay := linkLatency(rng, cfg.RTTMeanMs/2, cfg.RTTStddevMs/2)
+	heap.Push(q, Event{DeliverTime: now + delay, From: requester, To: provider, MsgID: msgID, Kind: EventIWant, Variant: variant})
+}
+
+func scheduleDeliver(q *eventQueue, cfg Options, provider, requester, msgID, variant int, now float64, rng *rand.Rand, bytesOut []int64, perMessageBytes int) {
+	delay := linkLatency(rng, cfg.RTTMeanMs/2, cfg.RTTStddevMs/2) + transmissionDelayMs(perMessageBytes, cfg.LinkBandwidthBytesPerSec)
+	bytesOut[provider] += int64(perMessageBytes)
+	heap.Push(q, Event{DeliverTime: now + delay, From: provider, To: requester, MsgID: msgID, Kind: EventDeliver, Variant: variant})
+}
+
+// linkLatency samples a one-way link delay in ms from a normal distribution,
+// clamped to a small positive floor so simultaneous events stay well ordered.
+func linkLatency(rng *rand.Rand, meanMs, stddevMs float64) float64 {
+	d := meanMs + rng.NormFloat64()*stddevMs
+	return math.Max(0.05, d)
+}
+
+func transmissionDelayMs(bytes, bandwidthBytesPerSec int) float64 {
+	if bandwidthBytesPerSec <= 0 {
+		return 0
+	}
+	return float64(bytes) / float64(bandwidthBytesPerSec) * 1000.0
+}
+
+// buildTopology constructs the mesh (push) adjacency list for n peers.
+func buildTopology(n, degree int, kind Topology, adjacency [][]int, rng *rand.Rand) [][]int {
+	if kind == TopologyExplicit && adjacency != nil {
+		return adjacency
+	}
+	if degree > n-1 {
+		degree = n - 1
+	}
+	if degree < 1 {
+		degree = 1
+	}
+	adj := make([][]int, n)
+
+	if kind == TopologySmallWorld {
+		half := degree / 2
+		if half < 1 {
+			half = 1
+		}
+		for i := 0; i < n; i++ {
+			for d := 1; d <= half; d++ {
+				adj[i] = append(adj[i], (i+d)%n, (i-d+n)%n)
+			}
+		}
+		const rewireProb = 0.1
+		for i := range adj {
+			for j := range adj[i] {
+				if rng.Float64() < rewireProb {
+					adj[i][j] = rng.IntN(n)
+				}
+			}
+		}
+		return adj
+	}
+
+	for i := 0; i < n; i++ {
+		seen := make(map[int]bool, degree)
+		for len(adj[i]) < degree {
+			p := rng.IntN(n)
+			if p == i || seen[p] {
+				continue
+			}
+			seen[p] = true
+			adj[i] = append(adj[i], p)
+		}
+	}
+	return adj
+}
+
+// buildLazyPeers picks fanout peers per node, disjoint from its mesh peers,
+// used for the lazy IHAVE/IWANT pull path.
+func buildLazyPeers(n, fanout int, mesh [][]int, rng *rand.Rand) [][]int {
+	if fanout > n-1 {
+		fanout = n - 1
+	}
+	if fanout < 0 {
+		fanout = 0
+	}
+	lazy := make([][]int, n)
+	for i := 0; i < n; i++ {
+		inMesh := make(map[int]bool, len(mesh[i]))
+		for _, p := range mesh[i] {
+			inMesh[p] = true
+		}
+		picked := make(map[int]bool, fanout)
+		for attempts := 0; len(lazy[i]) < fanout && attempts < fanout*20+20; attempts++ {
+			p := rng.IntN(n)
+			if p == i || inMesh[p] || picked[p] {
+				continue
+			}
+			picked[p] = true
+			lazy[i] = append(lazy[i], p)
+		}
+	}
+	return lazy
+}
+
+func mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+func meanOrDefault(xs []float64, def float64) float64 {
+	if len(xs) == 0 {
+		return def
+	}
+	return mean(xs)
+}
+
+// percentile expects sorted to be pre-sorted ascending.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
 }
 
 func normalize(opts Options) Options {
@@ -72,5 +655,38 @@ func normalize(opts Options) Options {
 	if cfg.Seed == 0 {
 		cfg.Seed = 1
 	}
+	if cfg.Degree <= 0 {
+		cfg.Degree = defaultDegree
+	}
+	if cfg.FanOut <= 0 {
+		cfg.FanOut = defaultFanOut
+	}
+	if cfg.LinkBandwidthBytesPerSec <= 0 {
+		cfg.LinkBandwidthBytesPerSec = defaultBandwidth
+	}
+	if cfg.RTTMeanMs <= 0 {
+		cfg.RTTMeanMs = defaultRTTMeanMs
+	}
+	if cfg.RTTStddevMs <= 0 {
+		cfg.RTTStddevMs = defaultRTTStddevMs
+	}
+	if cfg.LossRate < 0 {
+		cfg.LossRate = 0
+	}
+	if cfg.LossRate > 1 {
+		cfg.LossRate = 1
+	}
+	if cfg.SignatureVerifyCostMs <= 0 {
+		cfg.SignatureVerifyCostMs = 0.5
+	}
+	if cfg.Cores <= 0 {
+		cfg.Cores = 1
+	}
+	if cfg.VerifyBatchSize <= 0 {
+		cfg.VerifyBatchSize = 1
+	}
+	if cfg.BatchSpeedup <= 0 {
+		cfg.BatchSpeedup = 1
+	}
 	return cfg
 }
@@ -1,6 +1,10 @@
 package sim
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/Pauli-Group/Hegemon/consensus/bench/sim/score"
+)
 
 func TestSimulateDeterministic(t *testing.T) {
 	metrics := Simulate(Options{Miners: 16, PayloadBytes: 2048, Iterations: 128, Seed: 42})
@@ -16,3 +20,90 @@ func TestSimulateDeterministic(t *testing.T) {
 		t.Fatalf("expected deterministic throughput")
 	}
 }
+
+func TestSimulateFullCoverage(t *testing.T) {
+	metrics := Simulate(Options{Miners: 32, PayloadBytes: 512, Iterations: 4, Seed: 7, Degree: 4, FanOut: 2})
+	if metrics.TimeToFullCoverageMs <= 0 {
+		t.Fatalf("expected a positive time-to-full-coverage, got %d", metrics.TimeToFullCoverageMs)
+	}
+	if metrics.P50LatencyMs <= 0 || metrics.P99LatencyMs < metrics.P50LatencyMs {
+		t.Fatalf("expected sane latency percentiles, got p50=%.2f p99=%.2f", metrics.P50LatencyMs, metrics.P99LatencyMs)
+	}
+	if metrics.DuplicateRatio < 0 || metrics.DuplicateRatio > 1 {
+		t.Fatalf("duplicate ratio out of range: %.4f", metrics.DuplicateRatio)
+	}
+}
+
+func TestSimulateTraceMatchesMetrics(t *testing.T) {
+	opts := Options{Miners: 8, PayloadBytes: 256, Iterations: 2, Seed: 99}
+	metrics, trace := SimulateTrace(opts)
+	if len(trace) == 0 {
+		t.Fatalf("expected a non-empty event trace")
+	}
+	if again := Simulate(opts); again.AvgLatencyMs != metrics.AvgLatencyMs {
+		t.Fatalf("SimulateTrace metrics diverged from Simulate: %.4f vs %.4f", metrics.AvgLatencyMs, again.AvgLatencyMs)
+	}
+}
+
+func TestSimulateScoreParamsReportsGrafts(t *testing.T) {
+	params := score.DefaultParams()
+	metrics := Simulate(Options{Miners: 20, Iterations: 3, Seed: 13, Degree: 5, ScoreParams: &params})
+	if metrics.Grafts != 20 {
+		t.Fatalf("expected one graft per peer, got %d", metrics.Grafts)
+	}
+	if metrics.Prunes < 0 || metrics.Graylistings < 0 {
+		t.Fatalf("prune/graylist counts should never be negative")
+	}
+}
+
+func TestSimulateScoreGatesForwarding(t *testing.T) {
+	base := Options{Miners: 20, Iterations: 2, Seed: 5, Degree: 5, FanOut: 2}
+	baseline := Simulate(base)
+
+	params := score.DefaultParams()
+	params.PublishThreshold = 1e9
+	params.GossipThreshold = 1e9
+	gated := base
+	gated.ScoreParams = &params
+	restricted := Simulate(gated)
+
+	if restricted.BytesOutPerPeer >= baseline.BytesOutPerPeer {
+		t.Fatalf("expected peers below the publish/gossip thresholds to stop forwarding and stop being gossiped to: baseline=%.2f restricted=%.2f", baseline.BytesOutPerPeer, restricted.BytesOutPerPeer)
+	}
+}
+
+func TestSimulateWithoutScoreParamsReportsZero(t *testing.T) {
+	metrics := Simulate(Options{Miners: 10, Iterations: 1, Seed: 1})
+	if metrics.Grafts != 0 || metrics.Prunes != 0 || metrics.Graylistings != 0 {
+		t.Fatalf("expected zeroed score metrics without ScoreParams, got %+v", metrics)
+	}
+}
+
+func TestSimulateDurationIsMaxOfNetAndCPUBound(t *testing.T) {
+	base := Options{Miners: 16, PayloadBytes: 2048, Iterations: 32, Seed: 42}
+	fast := Simulate(base)
+	if float64(fast.DurationMs)+1 < fast.NetBoundMs || float64(fast.DurationMs)+1 < fast.CPUBoundMs {
+		t.Fatalf("expected duration to be at least as large as both bound components: duration=%d net=%.2f cpu=%.2f", fast.DurationMs, fast.NetBoundMs, fast.CPUBoundMs)
+	}
+
+	heavy := base
+	heavy.PQScheme = SPHINCSPlus128s
+	slow := Simulate(heavy)
+	if slow.CPUBoundMs <= fast.CPUBoundMs {
+		t.Fatalf("expected SPHINCS+-128s to cost more CPU time than the default scheme")
+	}
+	if float64(slow.DurationMs)+1 < slow.CPUBoundMs {
+		t.Fatalf("expected a heavier scheme's larger CPU-bound time to be reflected in duration: duration=%d cpu=%.2f", slow.DurationMs, slow.CPUBoundMs)
+	}
+}
+
+func TestSimulateMoreCoresReducesCPUBound(t *testing.T) {
+	base := Options{Miners: 16, PayloadBytes: 2048, Iterations: 32, Seed: 42, PQScheme: SPHINCSPlus128s}
+	one := Simulate(base)
+	eight := base
+	eight.Cores = 8
+	many := Simulate(eight)
+	if many.CPUBoundMs >= one.CPUBoundMs {
+		t.Fatalf("expected more cores to reduce CPU-bound time: 1 core=%.2f 8 cores=%.2f", one.CPUBoundMs, many.CPUBoundMs)
+	}
+}